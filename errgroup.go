@@ -8,8 +8,10 @@ package errgroup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 )
 
 type Group interface {
@@ -19,10 +21,56 @@ type Group interface {
 	Cancel() error
 	Done() <-chan struct{}
 	Wait() error
+
+	// WaitContext blocks like Wait, but returns ctx.Err() as soon as ctx is
+	// done, without canceling the group itself: goroutines started with Go
+	// or TryGo keep running under the group's own context and can still be
+	// waited on or canceled later.
+	WaitContext(ctx context.Context) error
+
+	// GoTimeout is like Go, but f is given a context derived from the
+	// group's own context with an added deadline of d.
+	GoTimeout(d time.Duration, f func(ctx context.Context) error)
+
+	// Errors returns every non-nil error recorded by the group so far, in
+	// the order they were observed. It is safe to call concurrently with
+	// Go, TryGo and Wait.
+	Errors() []error
+
+	// Stats returns a snapshot of the group's goroutine activity. It is
+	// meaningful even without an Observer attached.
+	Stats() Stats
+
+	// SetWeightedLimit switches the group to a weighted-semaphore limiter
+	// of the given total capacity, for use with GoN and TryGoN.
+	SetWeightedLimit(capacity int64)
+
+	// GoN is like Go, but the task is weighted: it blocks until weight
+	// units of the group's weighted capacity are available.
+	GoN(weight int64, f func(ctx context.Context) error)
+
+	// TryGoN is like TryGo, but the task is weighted: it only starts f if
+	// weight units of the group's weighted capacity are immediately
+	// available.
+	TryGoN(weight int64, f func(ctx context.Context) error) bool
 }
 
 type token struct{}
 
+// Option configures a Group created by New.
+type Option func(*group)
+
+// WithAggregatedErrors makes Wait and Cancel return an errors.Join of every
+// non-nil error returned by the group's functions, instead of only the
+// first one. The group's context is still canceled on the first error.
+// Use errors.Is/errors.As against the returned error to test for a specific
+// cause, or call Errors to inspect them individually.
+func WithAggregatedErrors() Option {
+	return func(g *group) {
+		g.aggregate = true
+	}
+}
+
 // A group is a collection of goroutines working on subtasks that are part of
 // the same overall task.
 type group struct {
@@ -33,8 +81,23 @@ type group struct {
 
 	sem chan token
 
+	aggregate bool
+	recover   bool
+
 	errOnce sync.Once
 	err     error
+
+	mu   sync.Mutex
+	errs []error
+
+	observer Observer
+	taskID   uint64
+	active   int64
+	peak     int64
+	started  int64
+	failed   int64
+
+	wsem *weighted
 }
 
 func (g *group) done() {
@@ -49,27 +112,91 @@ func (g *group) done() {
 // The internal Context is canceled the first time a function passed to Go
 // returns a non-nil error or the first time Wait returns, whichever occurs
 // first.
-func New(ctx context.Context) Group {
+func New(ctx context.Context, opts ...Option) Group {
 	ctx, cancel := context.WithCancel(ctx)
-	return &group{ctx: ctx, cancel: cancel}
+	g := &group{ctx: ctx, cancel: cancel}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// recordErr records a non-nil error returned by one of the group's
+// functions, keeps it alongside every other error seen so far (for Errors,
+// and for Wait and Cancel when WithAggregatedErrors is set), and cancels the
+// group's context.
+func (g *group) recordErr(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+	})
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+	g.cancel()
 }
 
 func (g *group) Cancel() error {
 	g.cancel()
 	g.wg.Wait()
-	return g.err
+	return g.result()
 }
 
 func (g *group) Done() <-chan struct{} {
 	return g.ctx.Done()
 }
 
-// Wait blocks until all function calls from the Go method have returned, then
-// returns the first non-nil error (if any) from them.
+// Wait blocks until all function calls from the Go method have returned,
+// then returns the first non-nil error (if any) from them, or, when the
+// group was created with WithAggregatedErrors, an errors.Join of every
+// non-nil error.
 func (g *group) Wait() error {
 	g.wg.Wait()
 	g.cancel()
-	return g.err
+	return g.result()
+}
+
+// WaitContext blocks until either all function calls from the Go method
+// have returned or ctx is done, whichever occurs first. In the latter case
+// it returns ctx.Err() without canceling the group: outstanding goroutines
+// keep running and Wait (or Cancel) can still be called on g afterwards.
+func (g *group) WaitContext(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GoTimeout calls the given function in a new goroutine, like Go, with a
+// context derived from the group's own context and bounded by d.
+func (g *group) GoTimeout(d time.Duration, f func(ctx context.Context) error) {
+	g.Go(func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return f(ctx)
+	})
+}
+
+func (g *group) result() error {
+	if !g.aggregate {
+		return g.err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// Errors returns every non-nil error recorded by the group so far, in the
+// order they were observed.
+func (g *group) Errors() []error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
 }
 
 // Go calls the given function in a new goroutine.
@@ -79,19 +206,14 @@ func (g *group) Wait() error {
 // The first call to return a non-nil error cancels the group's context.
 // The error will be returned by Wait.
 func (g *group) Go(f func(ctx context.Context) error) {
-	if g.sem != nil {
-		g.sem <- token{}
-	}
+	g.acquire()
 
 	g.wg.Add(1)
 	go func() {
 		defer g.done()
 
-		if err := f(g.ctx); err != nil {
-			g.errOnce.Do(func() {
-				g.err = err
-				g.cancel()
-			})
+		if err := g.track(f); err != nil {
+			g.recordErr(err)
 		}
 	}()
 }
@@ -114,11 +236,8 @@ func (g *group) TryGo(f func(ctx context.Context) error) bool {
 	go func() {
 		defer g.done()
 
-		if err := f(g.ctx); err != nil {
-			g.errOnce.Do(func() {
-				g.err = err
-				g.cancel()
-			})
+		if err := g.track(f); err != nil {
+			g.recordErr(err)
 		}
 	}()
 	return true
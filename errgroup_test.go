@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -237,6 +238,155 @@ func TestGoLimit(t *testing.T) {
 	}
 }
 
+func TestAggregatedErrors(t *testing.T) {
+	err1 := errors.New("errgroup_test: 1")
+	err2 := errors.New("errgroup_test: 2")
+
+	g := errgroup.New(context.Background(), errgroup.WithAggregatedErrors())
+	g.Go(func(_ context.Context) error { return err1 })
+	g.Go(func(_ context.Context) error { return nil })
+	g.Go(func(_ context.Context) error { return err2 })
+
+	err := g.Wait()
+	if !errors.Is(err, err1) {
+		t.Errorf("g.Wait() = %v; want it to wrap %v", err, err1)
+	}
+	if !errors.Is(err, err2) {
+		t.Errorf("g.Wait() = %v; want it to wrap %v", err, err2)
+	}
+
+	errs := g.Errors()
+	if len(errs) != 2 {
+		t.Errorf("g.Errors() = %v; want 2 errors", errs)
+	}
+}
+
+func TestWithRecover(t *testing.T) {
+	g := errgroup.New(context.Background(), errgroup.WithRecover())
+	g.Go(func(_ context.Context) error { return nil })
+	g.Go(func(_ context.Context) error { panic("boom") })
+	g.Go(func(_ context.Context) error { return nil })
+
+	err := g.Wait()
+	var panicErr *errgroup.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("g.Wait() = %v; want a *errgroup.PanicError", err)
+	}
+	if panicErr.Value != "boom" {
+		t.Errorf("panicErr.Value = %v; want %q", panicErr.Value, "boom")
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Error("panicErr.Stack is empty; want a captured stack trace")
+	}
+
+	canceled := false
+	select {
+	case <-g.Done():
+		canceled = true
+	default:
+	}
+	if !canceled {
+		t.Error("panic did not cancel the group's context")
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	g := errgroup.New(context.Background())
+	release := make(chan struct{})
+	g.Go(func(_ context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.WaitContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("g.WaitContext(ctx) = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	select {
+	case <-g.Done():
+		t.Fatal("WaitContext timing out canceled the group")
+	default:
+	}
+
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("g.Wait() = %v; want nil", err)
+	}
+}
+
+func TestGoTimeout(t *testing.T) {
+	g := errgroup.New(context.Background())
+	g.GoTimeout(10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := g.Wait(); err != context.DeadlineExceeded {
+		t.Fatalf("g.Wait() = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+type recordingObserver struct {
+	mu          sync.Mutex
+	started     []uint64
+	done        []uint64
+	limitBlocks int
+}
+
+func (o *recordingObserver) OnGo(taskID uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started = append(o.started, taskID)
+}
+
+func (o *recordingObserver) OnDone(taskID uint64, err error, dur time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.done = append(o.done, taskID)
+}
+
+func (o *recordingObserver) OnLimitBlocked(dur time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.limitBlocks++
+}
+
+func TestWithObserver(t *testing.T) {
+	obs := &recordingObserver{}
+	g := errgroup.New(context.Background(), errgroup.WithObserver(obs))
+	g.SetLimit(1)
+
+	errBoom := errors.New("errgroup_test: boom")
+	g.Go(func(_ context.Context) error { return nil })
+	g.Go(func(_ context.Context) error { return errBoom })
+	g.Go(func(_ context.Context) error { return nil })
+	g.Wait()
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if len(obs.started) != 3 || len(obs.done) != 3 {
+		t.Fatalf("observer saw %d starts and %d completions; want 3 and 3", len(obs.started), len(obs.done))
+	}
+	if obs.limitBlocks == 0 {
+		t.Error("observer saw no OnLimitBlocked calls; want at least 1 with SetLimit(1)")
+	}
+
+	stats := g.Stats()
+	if stats.Started != 3 {
+		t.Errorf("stats.Started = %d; want 3", stats.Started)
+	}
+	if stats.Failed != 1 {
+		t.Errorf("stats.Failed = %d; want 1", stats.Failed)
+	}
+	if stats.Active != 0 {
+		t.Errorf("stats.Active = %d; want 0 after Wait", stats.Active)
+	}
+	if stats.Peak < 1 {
+		t.Errorf("stats.Peak = %d; want at least 1", stats.Peak)
+	}
+}
+
 func BenchmarkGo(b *testing.B) {
 	fn := func(_ context.Context) {}
 	g := errgroup.New(context.Background())
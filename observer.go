@@ -0,0 +1,127 @@
+package errgroup
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Observer receives lifecycle events for a group's goroutines. It can be
+// used to wire a Group to a metrics or tracing backend (e.g. Prometheus or
+// OpenTelemetry, with a span per task rooted in the caller's span) without
+// reimplementing Go.
+//
+// Hooks are called from the worker goroutine itself, immediately before and
+// after the task function runs. A panicking hook is recovered and ignored
+// so it can never deadlock the group.
+type Observer interface {
+	// OnGo is called just before a task starts, with a group-scoped
+	// monotonically increasing task ID.
+	OnGo(taskID uint64)
+	// OnDone is called just after a task returns, with the same taskID
+	// passed to OnGo, the error it returned (if any), and how long it ran.
+	OnDone(taskID uint64, err error, dur time.Duration)
+	// OnLimitBlocked is called after a call to Go had to wait for capacity
+	// under SetLimit, with how long it waited.
+	OnLimitBlocked(dur time.Duration)
+}
+
+// WithObserver attaches an Observer to the group, notified of every task
+// started with Go or TryGo.
+func WithObserver(o Observer) Option {
+	return func(g *group) {
+		g.observer = o
+	}
+}
+
+// Stats is a snapshot of a group's goroutine activity, as returned by
+// Group.Stats.
+type Stats struct {
+	// Active is the number of tasks currently running.
+	Active int64
+	// Peak is the highest number of tasks that have been active at once.
+	Peak int64
+	// Started is the total number of tasks started with Go, TryGo, GoN or
+	// TryGoN.
+	Started int64
+	// Failed is the total number of tasks that returned a non-nil error.
+	Failed int64
+}
+
+func (g *group) notifyOnGo(id uint64) {
+	if g.observer == nil {
+		return
+	}
+	defer func() { recover() }()
+	g.observer.OnGo(id)
+}
+
+func (g *group) notifyOnDone(id uint64, err error, dur time.Duration) {
+	if g.observer == nil {
+		return
+	}
+	defer func() { recover() }()
+	g.observer.OnDone(id, err, dur)
+}
+
+func (g *group) notifyLimitBlocked(dur time.Duration) {
+	if g.observer == nil {
+		return
+	}
+	defer func() { recover() }()
+	g.observer.OnLimitBlocked(dur)
+}
+
+// acquire reserves one slot of the group's SetLimit capacity, reporting how
+// long it had to block (if at all) to the group's Observer.
+func (g *group) acquire() {
+	if g.sem == nil {
+		return
+	}
+	select {
+	case g.sem <- token{}:
+		return
+	default:
+	}
+	start := time.Now()
+	g.sem <- token{}
+	g.notifyLimitBlocked(time.Since(start))
+}
+
+// track runs f, updating the group's Stats and notifying its Observer
+// before and after the call.
+func (g *group) track(f func(ctx context.Context) error) error {
+	id := atomic.AddUint64(&g.taskID, 1)
+	g.notifyOnGo(id)
+
+	active := atomic.AddInt64(&g.active, 1)
+	atomic.AddInt64(&g.started, 1)
+	for {
+		peak := atomic.LoadInt64(&g.peak)
+		if active <= peak || atomic.CompareAndSwapInt64(&g.peak, peak, active) {
+			break
+		}
+	}
+
+	start := time.Now()
+	err := g.call(f)
+	dur := time.Since(start)
+
+	atomic.AddInt64(&g.active, -1)
+	if err != nil {
+		atomic.AddInt64(&g.failed, 1)
+	}
+	g.notifyOnDone(id, err, dur)
+	return err
+}
+
+// Stats returns a snapshot of the group's current and lifetime goroutine
+// activity.
+func (g *group) Stats() Stats {
+	return Stats{
+		Active:  atomic.LoadInt64(&g.active),
+		Peak:    atomic.LoadInt64(&g.peak),
+		Started: atomic.LoadInt64(&g.started),
+		Failed:  atomic.LoadInt64(&g.failed),
+	}
+}
@@ -0,0 +1,47 @@
+package errgroup
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// WithRecover makes the group recover panics raised by functions passed to
+// Go or TryGo. A recovered panic is converted into a *PanicError, recorded
+// and returned by Wait exactly like any other error, and the group's
+// context is canceled as usual. Without this option, a panicking function
+// crashes the program and leaves Wait blocked forever, since the goroutine
+// never reaches wg.Done.
+func WithRecover() Option {
+	return func(g *group) {
+		g.recover = true
+	}
+}
+
+// PanicError wraps a value recovered from a panic raised by a function
+// passed to Go or TryGo, together with the stack trace captured at the
+// point of the panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace captured by runtime/debug.Stack at the
+	// point of the panic.
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("errgroup: task panicked: %v\n%s", p.Value, p.Stack)
+}
+
+// call runs f, converting a panic into a *PanicError when the group was
+// created with WithRecover.
+func (g *group) call(f func(ctx context.Context) error) (err error) {
+	if g.recover {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+	}
+	return f(g.ctx)
+}
@@ -0,0 +1,98 @@
+package errgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pipeline runs a single producer goroutine that streams items of type T
+// into a fixed-size pool of worker goroutines transforming them into
+// results of type R, and collects whatever those workers write to a
+// downstream channel. It is built on top of Group, so a non-nil error from
+// the producer or from any worker cancels the whole pipeline, same as
+// Group.Go.
+//
+// The functions passed to Produce and Process are responsible for
+// respecting ctx cancellation on every channel operation; a producer or
+// worker that blocks sending on out after ctx is done will leak.
+type Pipeline[T, R any] struct {
+	workers int
+
+	g   Group
+	in  chan T
+	out chan R
+
+	produce func(ctx context.Context, out chan<- T) error
+	process func(ctx context.Context, in <-chan T, out chan<- R) error
+}
+
+// NewPipeline returns a new Pipeline with the given number of concurrent
+// worker goroutines for its Process stage. workers must be at least 1,
+// since a Process stage is what drains the producer; NewPipeline panics
+// otherwise.
+func NewPipeline[T, R any](ctx context.Context, workers int) *Pipeline[T, R] {
+	if workers < 1 {
+		panic(fmt.Errorf("errgroup: pipeline workers must be at least 1, got %d", workers))
+	}
+	return &Pipeline[T, R]{
+		workers: workers,
+		g:       New(ctx),
+		in:      make(chan T),
+		out:     make(chan R),
+	}
+}
+
+// Produce registers the function run in the pipeline's single producer
+// goroutine. f must stream items on out and return once it has nothing
+// left to produce, or ctx is done; the pipeline closes out on f's return.
+func (p *Pipeline[T, R]) Produce(f func(ctx context.Context, out chan<- T) error) {
+	p.produce = f
+}
+
+// Process registers the function run in each of the pipeline's worker
+// goroutines. f must read from in until it is closed (or ctx is done) and
+// write its results to out.
+func (p *Pipeline[T, R]) Process(f func(ctx context.Context, in <-chan T, out chan<- R) error) {
+	p.process = f
+}
+
+// Collect starts the producer and worker stages, waits for them to finish,
+// and returns everything written to the Process stage's out channel. It
+// returns the first error returned by the producer or by any worker.
+func (p *Pipeline[T, R]) Collect() ([]R, error) {
+	p.g.SetLimit(p.workers + 1)
+
+	p.g.Go(func(ctx context.Context) error {
+		defer close(p.in)
+		return p.produce(ctx, p.in)
+	})
+
+	var workers sync.WaitGroup
+	workers.Add(p.workers)
+	for i := 0; i < p.workers; i++ {
+		p.g.Go(func(ctx context.Context) error {
+			defer workers.Done()
+			return p.process(ctx, p.in, p.out)
+		})
+	}
+	go func() {
+		workers.Wait()
+		close(p.out)
+	}()
+
+	var (
+		results []R
+		done    = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for r := range p.out {
+			results = append(results, r)
+		}
+	}()
+
+	err := p.g.Wait()
+	<-done
+	return results, err
+}
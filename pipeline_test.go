@@ -0,0 +1,111 @@
+package errgroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"go.linka.cloud/errgroup"
+)
+
+func TestNewPipelineRejectsNonPositiveWorkers(t *testing.T) {
+	for _, workers := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewPipeline(ctx, %d) did not panic", workers)
+				}
+			}()
+			errgroup.NewPipeline[int, string](context.Background(), workers)
+		}()
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	const n = 20
+
+	p := errgroup.NewPipeline[int, string](context.Background(), 4)
+	p.Produce(func(ctx context.Context, out chan<- int) error {
+		for i := 0; i < n; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	p.Process(func(ctx context.Context, in <-chan int, out chan<- string) error {
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+				select {
+				case out <- fmt.Sprintf("%d", v*v):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+
+	results, err := p.Collect()
+	if err != nil {
+		t.Fatalf("p.Collect() returned error: %v", err)
+	}
+	if len(results) != n {
+		t.Fatalf("p.Collect() returned %d results; want %d", len(results), n)
+	}
+	sort.Strings(results)
+	want := make([]string, n)
+	for i := range want {
+		want[i] = fmt.Sprintf("%d", i*i)
+	}
+	sort.Strings(want)
+	for i, r := range results {
+		if r != want[i] {
+			t.Errorf("results[%d] = %q; want %q", i, r, want[i])
+		}
+	}
+}
+
+func TestPipelineError(t *testing.T) {
+	errBoom := errors.New("pipeline_test: boom")
+
+	p := errgroup.NewPipeline[int, string](context.Background(), 2)
+	p.Produce(func(ctx context.Context, out chan<- int) error {
+		for i := 0; i < 10; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	p.Process(func(ctx context.Context, in <-chan int, out chan<- string) error {
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+				if v == 5 {
+					return errBoom
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+
+	if _, err := p.Collect(); !errors.Is(err, errBoom) {
+		t.Fatalf("p.Collect() error = %v; want %v", err, errBoom)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errgroup
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrWeightExceedsCapacity is recorded, as if returned by the task itself,
+// when GoN or TryGoN is called with a weight greater than the group's
+// weighted limit capacity (see SetWeightedLimit): such a task could never
+// acquire enough capacity to run.
+var ErrWeightExceedsCapacity = errors.New("errgroup: task weight exceeds the group's weighted limit capacity")
+
+// weighted is a counting semaphore with a total capacity, similar in spirit
+// to golang.org/x/sync/semaphore.Weighted, except that acquire additionally
+// unblocks when the passed-in context is done.
+type weighted struct {
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+	waiters list.List
+}
+
+func newWeighted(size int64) *weighted {
+	return &weighted{size: size}
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// acquire blocks until n units of capacity are available or ctx is done.
+func (w *weighted) acquire(ctx context.Context, n int64) error {
+	w.mu.Lock()
+	if w.size-w.cur >= n && w.waiters.Len() == 0 {
+		w.cur += n
+		w.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	elem := w.waiters.PushBack(waiter{n: n, ready: ready})
+	w.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		err := ctx.Err()
+		w.mu.Lock()
+		select {
+		case <-ready:
+			// Acquired the semaphore after we were canceled; pretend we
+			// didn't notice and keep the capacity.
+			err = nil
+		default:
+			isFront := w.waiters.Front() == elem
+			w.waiters.Remove(elem)
+			if isFront && w.size > w.cur {
+				w.notifyWaiters()
+			}
+		}
+		w.mu.Unlock()
+		return err
+	case <-ready:
+		return nil
+	}
+}
+
+// tryAcquire acquires n units of capacity without blocking, reporting
+// whether it succeeded.
+func (w *weighted) tryAcquire(n int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size-w.cur >= n && w.waiters.Len() == 0 {
+		w.cur += n
+		return true
+	}
+	return false
+}
+
+// release returns n units of capacity to the semaphore.
+func (w *weighted) release(n int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cur -= n
+	if w.cur < 0 {
+		panic("errgroup: release of unacquired weighted capacity")
+	}
+	w.notifyWaiters()
+}
+
+func (w *weighted) notifyWaiters() {
+	for {
+		next := w.waiters.Front()
+		if next == nil {
+			break
+		}
+		wt := next.Value.(waiter)
+		if w.size-w.cur < wt.n {
+			break
+		}
+		w.cur += wt.n
+		w.waiters.Remove(next)
+		close(wt.ready)
+	}
+}
+
+// SetWeightedLimit switches the group to a weighted-semaphore limiter with
+// the given total capacity, for use with GoN and TryGoN. It is independent
+// of the limiter configured by SetLimit.
+//
+// The limit must not be modified while any weighted goroutines are active;
+// doing so panics, matching SetLimit's own contract.
+func (g *group) SetWeightedLimit(capacity int64) {
+	if g.wsem != nil {
+		g.wsem.mu.Lock()
+		inUse := g.wsem.cur != 0 || g.wsem.waiters.Len() != 0
+		g.wsem.mu.Unlock()
+		if inUse {
+			panic(fmt.Errorf("errgroup: modify weighted limit while goroutines are still using it"))
+		}
+	}
+	g.wsem = newWeighted(capacity)
+}
+
+// GoN calls the given function in a new goroutine, blocking until weight
+// units of the group's weighted capacity (see SetWeightedLimit) are
+// available. If weight exceeds the configured capacity, f is never called
+// and ErrWeightExceedsCapacity is recorded as if f had returned it.
+//
+// If SetWeightedLimit has not been called, GoN behaves like Go and weight
+// is ignored.
+func (g *group) GoN(weight int64, f func(ctx context.Context) error) {
+	wsem := g.wsem
+	if wsem == nil {
+		g.Go(f)
+		return
+	}
+	if weight > wsem.size {
+		g.recordErr(ErrWeightExceedsCapacity)
+		return
+	}
+	if err := wsem.acquire(g.ctx, weight); err != nil {
+		// The group's context is already done; there is nothing left to run.
+		return
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer wsem.release(weight)
+
+		if err := g.track(f); err != nil {
+			g.recordErr(err)
+		}
+	}()
+}
+
+// TryGoN is like GoN, but only starts f if weight units of capacity are
+// immediately available. It reports whether f was started.
+func (g *group) TryGoN(weight int64, f func(ctx context.Context) error) bool {
+	wsem := g.wsem
+	if wsem == nil {
+		return g.TryGo(f)
+	}
+	if weight > wsem.size {
+		g.recordErr(ErrWeightExceedsCapacity)
+		return false
+	}
+	if !wsem.tryAcquire(weight) {
+		return false
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer wsem.release(weight)
+
+		if err := g.track(f); err != nil {
+			g.recordErr(err)
+		}
+	}()
+	return true
+}
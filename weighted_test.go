@@ -0,0 +1,158 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errgroup_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.linka.cloud/errgroup"
+)
+
+func TestGoNRespectsWeightedLimit(t *testing.T) {
+	g := errgroup.New(context.Background())
+	g.SetWeightedLimit(10)
+
+	var active, peak int64
+	for i := 0; i < 20; i++ {
+		g.GoN(3, func(_ context.Context) error {
+			n := atomic.AddInt64(&active, 3)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&active, -3)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("g.Wait() = %v; want nil", err)
+	}
+	if peak > 10 {
+		t.Errorf("peak weighted usage = %d; want <= 10", peak)
+	}
+}
+
+func TestGoNWeightExceedsCapacity(t *testing.T) {
+	g := errgroup.New(context.Background())
+	g.SetWeightedLimit(5)
+
+	var ran bool
+	g.GoN(10, func(_ context.Context) error {
+		ran = true
+		return nil
+	})
+	if err := g.Wait(); !errors.Is(err, errgroup.ErrWeightExceedsCapacity) {
+		t.Fatalf("g.Wait() = %v; want %v", err, errgroup.ErrWeightExceedsCapacity)
+	}
+	if ran {
+		t.Error("task with weight > capacity should not have run")
+	}
+}
+
+func TestGoNDrainsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := errgroup.New(ctx)
+	g.SetWeightedLimit(1)
+
+	release := make(chan struct{})
+	g.GoN(1, func(_ context.Context) error {
+		<-release
+		return nil
+	})
+
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		g.GoN(1, func(_ context.Context) error { return nil })
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	close(release)
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("second GoN call did not return after context cancellation")
+	}
+	g.Wait()
+}
+
+func TestSetWeightedLimitPanicsWhileInUse(t *testing.T) {
+	g := errgroup.New(context.Background())
+	g.SetWeightedLimit(4)
+
+	release := make(chan struct{})
+	g.GoN(1, func(_ context.Context) error {
+		<-release
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("SetWeightedLimit did not panic while a weighted goroutine was active")
+			}
+		}()
+		g.SetWeightedLimit(8)
+	}()
+
+	close(release)
+	g.Wait()
+}
+
+func TestGoNReleasesAgainstAcquiringSemaphore(t *testing.T) {
+	g := errgroup.New(context.Background())
+	g.SetWeightedLimit(2)
+
+	release := make(chan struct{})
+	g.GoN(2, func(_ context.Context) error {
+		<-release
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	g.Wait()
+
+	// SetWeightedLimit is only safe to call again once the prior weighted
+	// goroutine has actually finished; by the time Wait returns above it
+	// has, so this must not corrupt accounting on the old semaphore.
+	g.SetWeightedLimit(2)
+	if !g.TryGoN(2, func(_ context.Context) error { return nil }) {
+		t.Error("TryGoN failed against a freshly reset weighted limit")
+	}
+	g.Wait()
+}
+
+func TestTryGoN(t *testing.T) {
+	g := errgroup.New(context.Background())
+	g.SetWeightedLimit(2)
+
+	release := make(chan struct{})
+	g.GoN(2, func(_ context.Context) error {
+		<-release
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	if g.TryGoN(1, func(_ context.Context) error { return nil }) {
+		t.Error("TryGoN succeeded but capacity should have been exhausted")
+	}
+	close(release)
+	g.Wait()
+
+	if !g.TryGoN(2, func(_ context.Context) error { return nil }) {
+		t.Error("TryGoN failed but capacity should have been free")
+	}
+	g.Wait()
+}